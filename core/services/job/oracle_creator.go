@@ -0,0 +1,32 @@
+package job
+
+// OracleCreator constructs the long-running Services that back an
+// offchainreporting job spec. It exists so that job spawner delegates don't
+// hard-code a single oracle implementation: a delegate registers one
+// OracleCreator per plugin type (e.g. "median", "commit", "execute") and
+// dispatches to it based on the spec, which lets several reporting plugins
+// share one on-chain config tracker and peer wrapper under the same contract
+// address, and lets newer oracle implementations (OCR3's per-plugin
+// ReportingPluginFactory model, for example) be added without touching the
+// delegate itself.
+type OracleCreator interface {
+	// CreatePluginOracle constructs the Services that run a single reporting
+	// plugin instance against the given config (the oracle itself, plus any
+	// auxiliary services such as a monitoring endpoint client).
+	CreatePluginOracle(pluginType string, config OracleConfig) ([]Service, error)
+
+	// CreateBootstrapOracle constructs the Services that only track on-chain
+	// config and participate in bootstrapping, without running any
+	// reporting plugin.
+	CreateBootstrapOracle(config OracleConfig) ([]Service, error)
+}
+
+// OracleConfig is the spec-derived configuration an OracleCreator needs in
+// order to build an oracle or bootstrap Service. It is deliberately just the
+// Spec plus the job ID rather than a fixed set of OCR1-specific fields, so
+// that creators for different oracle generations can pull whatever they need
+// off the spec themselves.
+type OracleConfig struct {
+	JobID int32
+	Spec  Spec
+}
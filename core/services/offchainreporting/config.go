@@ -0,0 +1,75 @@
+package offchainreporting
+
+import (
+	"github.com/smartcontractkit/chainlink/core/services/job"
+	"github.com/smartcontractkit/chainlink/core/services/pipeline"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+)
+
+// DefaultPluginType is used when a spec doesn't specify one, so that existing
+// job specs written before PluginType existed keep running the original
+// median-report oracle unmodified.
+const DefaultPluginType = "median"
+
+// OracleSpec is the job.Spec for offchainreporting jobs. PluginType selects
+// which registered job.OracleCreator builds the oracle Service for this spec;
+// it defaults to DefaultPluginType so a single OCR contract address can also
+// host non-median plugins (e.g. CCIP's "commit"/"execute" pair) by pointing
+// several specs with different PluginTypes at the same ContractAddress.
+type OracleSpec struct {
+	// PluginType is promoted from the embedded model below (not redeclared
+	// here) specifically so that ToDBRow/FromDBRow - which only copy
+	// OffchainReportingOracleSpec in and out of the DB row - carry it across
+	// a job reload or node restart instead of silently dropping it back to
+	// the DefaultPluginType every time.
+	models.OffchainReportingOracleSpec
+
+	// ObservationAggregation selects how dataSource.Observe combines the
+	// results of an observationSource that fans out to several parallel
+	// branches instead of ending in a single terminal task. Defaults to
+	// pipeline.AggregationMedian, which also covers the traditional single-
+	// `median` task spec (a median of one value is that value).
+	ObservationAggregation string `toml:"observationAggregation"`
+
+	// MaxAllowedFaultyObservations caps how many of the observationSource's
+	// parallel branches are allowed to error before Observe gives up instead
+	// of aggregating over whatever succeeded.
+	MaxAllowedFaultyObservations int `toml:"maxAllowedFaultyObservations"`
+
+	// ObservationWeights gives each terminal task's DOT ID a weight, used
+	// only by ObservationAggregation = "weighted_median".
+	ObservationWeights map[string]float64 `toml:"observationWeights"`
+
+	jobID int32
+}
+
+func (spec OracleSpec) JobID() int32 {
+	return spec.jobID
+}
+
+// pluginType returns spec.PluginType, defaulting it for specs written before
+// this field existed.
+func (spec OracleSpec) pluginType() string {
+	if spec.PluginType == "" {
+		return DefaultPluginType
+	}
+	return spec.PluginType
+}
+
+// aggregationStrategy returns spec.ObservationAggregation, defaulting it for
+// specs written before fan-out observationSources existed.
+func (spec OracleSpec) aggregationStrategy() pipeline.AggregationStrategy {
+	if spec.ObservationAggregation == "" {
+		return pipeline.AggregationMedian
+	}
+	return pipeline.AggregationStrategy(spec.ObservationAggregation)
+}
+
+func (spec OracleSpec) aggregateOpts() pipeline.AggregateOpts {
+	return pipeline.AggregateOpts{
+		MaxAllowedFaultyObservations: spec.MaxAllowedFaultyObservations,
+		Weights:                      spec.ObservationWeights,
+	}
+}
+
+var _ job.Spec = OracleSpec{}
@@ -0,0 +1,12 @@
+package offchainreporting
+
+import "github.com/pkg/errors"
+
+// Sentinel errors returned by ocr1OracleCreator (and any future
+// job.OracleCreator) so that web.From can classify them into a specific
+// APIError code instead of a generic 500.
+var (
+	ErrKeyBundleMissing          = errors.New("offchainreporting: OCR key bundle does not exist")
+	ErrPeerNotStarted            = errors.New("offchainreporting: libp2p peer is not started")
+	ErrInvalidTransmitterAddress = errors.New("offchainreporting: invalid transmitter address")
+)
@@ -0,0 +1,238 @@
+package offchainreporting
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+
+	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/smartcontractkit/chainlink/core/services/eth"
+	"github.com/smartcontractkit/chainlink/core/services/job"
+	"github.com/smartcontractkit/chainlink/core/services/pipeline"
+	"github.com/smartcontractkit/chainlink/core/services/synchronization"
+	"github.com/smartcontractkit/chainlink/core/services/telemetry"
+	"github.com/smartcontractkit/chainlink/core/store/orm"
+	"github.com/smartcontractkit/libocr/gethwrappers/offchainaggregator"
+	ocr "github.com/smartcontractkit/libocr/offchainreporting"
+	ocrtypes "github.com/smartcontractkit/libocr/offchainreporting/types"
+)
+
+// ocr1OracleCreator is the job.OracleCreator backing the original
+// libocr/offchainreporting Oracle. It's registered under DefaultPluginType so
+// that existing "median" specs behave exactly as before the OracleCreator
+// indirection was introduced.
+type ocr1OracleCreator struct {
+	db             *gorm.DB
+	jobORM         job.ORM
+	config         *orm.Config
+	keyStore       *KeyStore
+	pipelineRunner pipeline.Runner
+	ethClient      eth.Client
+	logBroadcaster eth.LogBroadcaster
+	peerWrapper    *SingletonPeerWrapper
+}
+
+func newOCR1OracleCreator(
+	db *gorm.DB,
+	jobORM job.ORM,
+	config *orm.Config,
+	keyStore *KeyStore,
+	pipelineRunner pipeline.Runner,
+	ethClient eth.Client,
+	logBroadcaster eth.LogBroadcaster,
+	peerWrapper *SingletonPeerWrapper,
+) *ocr1OracleCreator {
+	return &ocr1OracleCreator{db, jobORM, config, keyStore, pipelineRunner, ethClient, logBroadcaster, peerWrapper}
+}
+
+var _ job.OracleCreator = (*ocr1OracleCreator)(nil)
+
+func (c *ocr1OracleCreator) CreateBootstrapOracle(oc job.OracleConfig) (services []job.Service, err error) {
+	concreteSpec, ocrContract, bootstrapPeers, lc, ocrLogger, monitoringEndpoint, services, err := c.commonServices(oc)
+	if err != nil {
+		return nil, err
+	}
+
+	bootstrapper, err := ocr.NewBootstrapNode(ocr.BootstrapNodeArgs{
+		BootstrapperFactory:   c.peerWrapper.Peer,
+		Bootstrappers:         bootstrapPeers,
+		ContractConfigTracker: ocrContract,
+		Database:              NewDB(c.db.DB(), concreteSpec.ID),
+		LocalConfig:           lc,
+		Logger:                ocrLogger,
+		MonitoringEndpoint:    monitoringEndpoint,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error calling NewBootstrapNode")
+	}
+	return append(services, bootstrapper), nil
+}
+
+func (c *ocr1OracleCreator) CreatePluginOracle(pluginType string, oc job.OracleConfig) (services []job.Service, err error) {
+	concreteSpec, ocrContract, bootstrapPeers, lc, ocrLogger, monitoringEndpoint, services, err := c.commonServices(oc)
+	if err != nil {
+		return nil, err
+	}
+	if len(bootstrapPeers) < 1 {
+		return nil, errors.New("need at least one bootstrap peer")
+	}
+
+	kb, err := c.config.OCRKeyBundleID(concreteSpec.EncryptedOCRKeyBundleID)
+	if err != nil {
+		return nil, err
+	}
+	ocrkey, exists := c.keyStore.DecryptedOCRKey(kb)
+	if !exists {
+		return nil, errors.Wrapf(ErrKeyBundleMissing, "%v", concreteSpec.EncryptedOCRKeyBundleID)
+	}
+	contractABI, err := abi.JSON(strings.NewReader(offchainaggregator.OffchainAggregatorABI))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get contract ABI JSON")
+	}
+	contractCaller, err := offchainaggregator.NewOffchainAggregatorCaller(concreteSpec.ContractAddress.Address(), c.ethClient)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not instantiate NewOffchainAggregatorCaller")
+	}
+
+	ta, err := c.config.OCRTransmitterAddress(concreteSpec.TransmitterAddress)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidTransmitterAddress, err.Error())
+	}
+	contractTransmitter := NewOCRContractTransmitter(concreteSpec.ContractAddress.Address(), contractCaller, contractABI,
+		NewTransmitter(c.db.DB(), ta.Address(), c.config.EthGasLimitDefault()))
+
+	oracle, err := ocr.NewOracle(ocr.OracleArgs{
+		Database:                     NewDB(c.db.DB(), concreteSpec.ID),
+		Datasource: dataSource{
+			jobID:          concreteSpec.JobID(),
+			pipelineRunner: c.pipelineRunner,
+			aggregation:    concreteSpec.aggregationStrategy(),
+			aggregateOpts:  concreteSpec.aggregateOpts(),
+		},
+		LocalConfig:                  lc,
+		ContractTransmitter:          contractTransmitter,
+		ContractConfigTracker:        ocrContract,
+		PrivateKeys:                  &ocrkey,
+		BinaryNetworkEndpointFactory: c.peerWrapper.Peer,
+		MonitoringEndpoint:           monitoringEndpoint,
+		Logger:                       ocrLogger,
+		Bootstrappers:                bootstrapPeers,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error calling NewOracle")
+	}
+	return append(services, oracle), nil
+}
+
+// commonServices builds the pieces every OCR1 oracle (plugin or bootstrap)
+// needs: the contract config tracker, bootstrap peer list, local config, and
+// monitoring endpoint. It also returns the monitoring endpoint client (if
+// any) already appended to the services slice, since it has its own
+// lifecycle independent of the oracle/bootstrapper itself.
+func (c *ocr1OracleCreator) commonServices(oc job.OracleConfig) (
+	concreteSpec *OracleSpec,
+	ocrContract *OCRContractConfigTracker,
+	bootstrapPeers []ocrtypes.BootstrapperIdentity,
+	lc ocrtypes.LocalConfig,
+	ocrLogger *Logger,
+	monitoringEndpoint ocrtypes.MonitoringEndpoint,
+	services []job.Service,
+	err error,
+) {
+	concreteSpec, is := oc.Spec.(*OracleSpec)
+	if !is {
+		err = errors.Errorf("ocr1OracleCreator expects an *offchainreporting.OracleSpec, got %T", oc.Spec)
+		return
+	}
+
+	contractFilterer, err := offchainaggregator.NewOffchainAggregatorFilterer(concreteSpec.ContractAddress.Address(), c.ethClient)
+	if err != nil {
+		err = errors.Wrap(err, "could not instantiate NewOffchainAggregatorFilterer")
+		return
+	}
+	contractCaller, err := offchainaggregator.NewOffchainAggregatorCaller(concreteSpec.ContractAddress.Address(), c.ethClient)
+	if err != nil {
+		err = errors.Wrap(err, "could not instantiate NewOffchainAggregatorCaller")
+		return
+	}
+	ocrContract, err = NewOCRContractConfigTracker(
+		concreteSpec.ContractAddress.Address(),
+		contractFilterer,
+		contractCaller,
+		c.ethClient,
+		c.logBroadcaster,
+		concreteSpec.JobID(),
+		*logger.Default,
+	)
+	if err != nil {
+		err = errors.Wrap(err, "error calling NewOCRContract")
+		return
+	}
+
+	peerID, err := c.config.P2PPeerID(concreteSpec.P2PPeerID)
+	if err != nil {
+		return
+	}
+	if c.peerWrapper == nil {
+		err = errors.Wrap(ErrPeerNotStarted, "cannot setup OCR job service, libp2p peer was missing")
+		return
+	} else if !c.peerWrapper.IsStarted() {
+		err = errors.Wrap(ErrPeerNotStarted, "OCR jobs require a started and running peer. Did you forget to specify P2P_LISTEN_PORT?")
+		return
+	} else if c.peerWrapper.PeerID != peerID {
+		err = errors.Errorf("given peer with ID '%s' does not match OCR configured peer with ID: %s", c.peerWrapper.PeerID.String(), peerID.String())
+		return
+	}
+	bootstrapPeers, err = c.config.P2PBootstrapPeers(concreteSpec.P2PBootstrapPeers)
+	if err != nil {
+		return
+	}
+
+	loggerWith := logger.CreateLogger(logger.Default.With(
+		"contractAddress", concreteSpec.ContractAddress,
+		"jobID", concreteSpec.jobID))
+	ocrLogger = NewLogger(loggerWith, c.config.OCRTraceLogging(), func(msg string) {
+		c.jobORM.RecordError(context.Background(), oc.JobID, msg)
+	})
+
+	var endpointURL *url.URL
+	if me := c.config.OCRMonitoringEndpoint(concreteSpec.MonitoringEndpoint); me != "" {
+		endpointURL, err = url.Parse(me)
+		if err != nil {
+			err = errors.Wrapf(err, "invalid monitoring url: %s", me)
+			return
+		}
+	} else {
+		endpointURL = c.config.ExplorerURL()
+	}
+
+	if endpointURL != nil {
+		client := synchronization.NewExplorerClient(endpointURL, c.config.ExplorerAccessKey(), c.config.ExplorerSecret())
+		monitoringEndpoint = telemetry.NewAgent(client)
+		services = append(services, client)
+	} else {
+		monitoringEndpoint = ocrtypes.MonitoringEndpoint(nil)
+	}
+
+	lc = ocrtypes.LocalConfig{
+		BlockchainTimeout:                      c.config.OCRBlockchainTimeout(time.Duration(concreteSpec.BlockchainTimeout)),
+		ContractConfigConfirmations:            c.config.OCRContractConfirmations(concreteSpec.ContractConfigConfirmations),
+		ContractConfigTrackerPollInterval:      c.config.OCRContractPollInterval(time.Duration(concreteSpec.ContractConfigTrackerPollInterval)),
+		ContractConfigTrackerSubscribeInterval: c.config.OCRContractSubscribeInterval(time.Duration(concreteSpec.ContractConfigTrackerSubscribeInterval)),
+		ContractTransmitterTransmitTimeout:     c.config.OCRContractTransmitterTransmitTimeout(),
+		DatabaseTimeout:                        c.config.OCRDatabaseTimeout(),
+		DataSourceTimeout:                      c.config.OCRObservationTimeout(time.Duration(concreteSpec.ObservationTimeout)),
+	}
+	if err = ocr.SanityCheckLocalConfig(lc); err != nil {
+		return
+	}
+	logger.Info(fmt.Sprintf("OCR job using local config %+v", lc))
+
+	return
+}
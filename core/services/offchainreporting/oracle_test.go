@@ -0,0 +1,43 @@
+package offchainreporting
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/core/store/models"
+)
+
+func TestJobSpawnerDelegate_ToDBRowFromDBRow_RoundTripsPluginType(t *testing.T) {
+	t.Parallel()
+
+	d := jobSpawnerDelegate{}
+
+	spec := OracleSpec{
+		OffchainReportingOracleSpec: models.OffchainReportingOracleSpec{
+			ID:         7,
+			PluginType: "commit",
+		},
+	}
+
+	row := d.ToDBRow(spec)
+	require.NotNil(t, row.OffchainreportingOracleSpec)
+	assert.Equal(t, "commit", row.OffchainreportingOracleSpec.PluginType)
+
+	row.ID = spec.ID
+	roundTripped := d.FromDBRow(row).(*OracleSpec)
+	assert.Equal(t, "commit", roundTripped.pluginType())
+}
+
+func TestJobSpawnerDelegate_FromDBRow_DefaultsMissingPluginType(t *testing.T) {
+	t.Parallel()
+
+	d := jobSpawnerDelegate{}
+	row := models.JobSpecV2{
+		OffchainreportingOracleSpec: &models.OffchainReportingOracleSpec{ID: 1},
+	}
+
+	spec := d.FromDBRow(row).(*OracleSpec)
+	assert.Equal(t, DefaultPluginType, spec.pluginType())
+}
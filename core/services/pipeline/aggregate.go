@@ -0,0 +1,204 @@
+package pipeline
+
+import (
+	"math/big"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/smartcontractkit/chainlink/core/utils"
+)
+
+// AggregationStrategy selects how TaskRunResults.Aggregate combines the
+// values produced by several parallel observation branches (e.g. three
+// independent http->jsonparse pipelines) into the single *big.Int an OCR
+// DataSource has to return.
+type AggregationStrategy string
+
+const (
+	AggregationMedian         AggregationStrategy = "median"
+	AggregationMean           AggregationStrategy = "mean"
+	AggregationMode           AggregationStrategy = "mode"
+	AggregationFirstNonError  AggregationStrategy = "first_non_error"
+	AggregationWeightedMedian AggregationStrategy = "weighted_median"
+)
+
+// AggregateOpts carries the knobs Aggregate needs beyond the strategy
+// itself: how many of the inputs are allowed to have errored, and - for
+// AggregationWeightedMedian - the weight of each contributing task, keyed by
+// the DOT ID of the terminal task in its branch.
+type AggregateOpts struct {
+	MaxAllowedFaultyObservations int
+	Weights                      map[string]float64
+}
+
+// RawObservation is one branch's pre-aggregation result: the value (or
+// error) a single terminal task in a fan-out observationSource produced,
+// before Aggregate combines every branch into the single *big.Int an OCR
+// DataSource returns.
+type RawObservation struct {
+	DotID string      `json:"dotId"`
+	Value interface{} `json:"value,omitempty"`
+	Error string      `json:"error,omitempty"`
+}
+
+// Raw extracts trrs' per-branch RawObservations, in the same order as trrs
+// itself. It exists so a caller with access to the full pipeline.Run record
+// (e.g. the /v2/jobs/:id/runs index) can surface every branch's raw value
+// alongside Aggregate's single combined result, for debugging a fan-out
+// observationSource's aggregation.
+func (trrs TaskRunResults) Raw() []RawObservation {
+	raw := make([]RawObservation, len(trrs))
+	for i, trr := range trrs {
+		var dotID string
+		if trr.Task != nil {
+			dotID = trr.Task.DotID()
+		}
+		obs := RawObservation{DotID: dotID, Value: trr.Value}
+		if trr.Error != nil {
+			obs.Error = trr.Error.Error()
+		}
+		raw[i] = obs
+	}
+	return raw
+}
+
+// Aggregate combines the non-errored values in trrs using strategy, dropping
+// up to opts.MaxAllowedFaultyObservations errored results first. It's what
+// lets an OCR observationSource fan out to N independent branches instead of
+// ending in a single terminal `median` task.
+func (trrs TaskRunResults) Aggregate(strategy AggregationStrategy, opts AggregateOpts) (*big.Int, error) {
+	var faulty int
+	values := make([]*big.Int, 0, len(trrs))
+	weights := make([]float64, 0, len(trrs))
+	var missingWeightTask string
+	for _, trr := range trrs {
+		if trr.Error != nil {
+			faulty++
+			continue
+		}
+		asDecimal, err := utils.ToDecimal(trr.Value)
+		if err != nil {
+			faulty++
+			continue
+		}
+		values = append(values, asDecimal.BigInt())
+
+		var weight float64
+		var dotID string
+		if trr.Task != nil {
+			dotID = trr.Task.DotID()
+		}
+		if w, ok := opts.Weights[dotID]; ok {
+			weight = w
+		} else if missingWeightTask == "" {
+			missingWeightTask = dotID
+		}
+		weights = append(weights, weight)
+	}
+	if strategy == AggregationWeightedMedian && missingWeightTask != "" {
+		return nil, errors.Errorf("weighted_median: no weight configured for task %q", missingWeightTask)
+	}
+
+	if faulty > opts.MaxAllowedFaultyObservations {
+		return nil, errors.Errorf("too many faulty observations: %d (max allowed %d)", faulty, opts.MaxAllowedFaultyObservations)
+	}
+	if len(values) == 0 {
+		return nil, errors.New("no non-errored observations to aggregate")
+	}
+
+	switch strategy {
+	case AggregationMedian, "":
+		return median(values), nil
+	case AggregationMean:
+		return mean(values), nil
+	case AggregationMode:
+		return mode(values)
+	case AggregationFirstNonError:
+		return values[0], nil
+	case AggregationWeightedMedian:
+		return weightedMedian(values, weights)
+	default:
+		return nil, errors.Errorf("unknown observation aggregation strategy %q", strategy)
+	}
+}
+
+func median(values []*big.Int) *big.Int {
+	sorted := sortedCopy(values)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[mid]
+	}
+	sum := new(big.Int).Add(sorted[mid-1], sorted[mid])
+	return sum.Div(sum, big.NewInt(2))
+}
+
+func mean(values []*big.Int) *big.Int {
+	sum := new(big.Int)
+	for _, v := range values {
+		sum.Add(sum, v)
+	}
+	return sum.Div(sum, big.NewInt(int64(len(values))))
+}
+
+func mode(values []*big.Int) (*big.Int, error) {
+	sorted := sortedCopy(values)
+
+	var best *big.Int
+	bestCount := 0
+	for i := 0; i < len(sorted); {
+		j := i
+		for j < len(sorted) && sorted[j].Cmp(sorted[i]) == 0 {
+			j++
+		}
+		if count := j - i; count > bestCount {
+			bestCount = count
+			best = sorted[i]
+		}
+		i = j
+	}
+	if best == nil {
+		return nil, errors.New("mode: no values")
+	}
+	return best, nil
+}
+
+// weightedMedian returns the value at which the cumulative weight first
+// reaches half the total weight, which is the standard definition of a
+// weighted median.
+func weightedMedian(values []*big.Int, weights []float64) (*big.Int, error) {
+	if len(values) != len(weights) {
+		return nil, errors.New("weightedMedian: values/weights length mismatch")
+	}
+
+	type pair struct {
+		value  *big.Int
+		weight float64
+	}
+	pairs := make([]pair, len(values))
+	var totalWeight float64
+	for i := range values {
+		pairs[i] = pair{values[i], weights[i]}
+		totalWeight += weights[i]
+	}
+	if totalWeight <= 0 {
+		return nil, errors.New("weightedMedian: total weight must be positive; set a weight per task")
+	}
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].value.Cmp(pairs[j].value) < 0 })
+
+	var cumulative float64
+	for _, p := range pairs {
+		cumulative += p.weight
+		if cumulative >= totalWeight/2 {
+			return p.value, nil
+		}
+	}
+	return pairs[len(pairs)-1].value, nil
+}
+
+func sortedCopy(values []*big.Int) []*big.Int {
+	sorted := make([]*big.Int, len(values))
+	copy(sorted, values)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Cmp(sorted[j]) < 0 })
+	return sorted
+}
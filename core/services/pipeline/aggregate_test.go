@@ -0,0 +1,129 @@
+package pipeline_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/services/pipeline"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustResult(t *testing.T, value int64) pipeline.TaskRunResult {
+	t.Helper()
+	return pipeline.TaskRunResult{Value: big.NewInt(value)}
+}
+
+// jsonParseUSD stands in for a `jsonparse path="USD"` task, extracting the
+// same field a real jsonparse task would directly from an HTTPTask.Run
+// result, so the branches below stay genuinely http-driven rather than
+// hand-built, without this test depending on the Runner/DAG builder that
+// wires jsonparse tasks into an observationSource.
+func jsonParseUSD(t *testing.T, body string) string {
+	t.Helper()
+	var parsed struct {
+		USD string `json:"USD"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(body), &parsed))
+	return parsed.USD
+}
+
+func TestTaskRunResults_Aggregate_MultipleBranchesNoTerminalMedian(t *testing.T) {
+	t.Parallel()
+
+	// Three parallel http->jsonparse branches, no terminal `median` task:
+	// Observe should still produce a valid observation by aggregating. Each
+	// branch actually runs an HTTPTask against its own test server and
+	// parses the response, rather than starting from hand-built
+	// TaskRunResults, so this exercises the same HTTP leg a real
+	// observationSource would.
+	values := []string{"100", "102", "104"}
+	trrs := make(pipeline.TaskRunResults, len(values))
+	// httptest serves on loopback, which the default safe client refuses.
+	_, loopback, err := net.ParseCIDR("127.0.0.0/8")
+	require.NoError(t, err)
+	client := pipeline.NewSafeHTTPClient(pipeline.SafeHTTPClientConfig{AllowedIPs: []*net.IPNet{loopback}})
+	for i, v := range values {
+		upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"USD": "` + v + `"}`))
+		}))
+		defer upstream.Close()
+
+		task := pipeline.HTTPTask{URL: upstream.URL}
+		task.SetSafeHTTPClient(client)
+		httpResult := task.Run(pipeline.Vars{})
+		require.NoError(t, httpResult.Error)
+
+		trrs[i] = pipeline.TaskRunResult{Value: jsonParseUSD(t, httpResult.Value.(string))}
+	}
+
+	result, err := trrs.Aggregate(pipeline.AggregationMedian, pipeline.AggregateOpts{})
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(102), result)
+}
+
+func TestTaskRunResults_Aggregate_DropsFaultyObservationsUpToLimit(t *testing.T) {
+	t.Parallel()
+
+	trrs := pipeline.TaskRunResults{
+		mustResult(t, 100),
+		{Error: assert.AnError},
+		mustResult(t, 104),
+	}
+
+	result, err := trrs.Aggregate(pipeline.AggregationMean, pipeline.AggregateOpts{MaxAllowedFaultyObservations: 1})
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(102), result)
+
+	_, err = trrs.Aggregate(pipeline.AggregationMean, pipeline.AggregateOpts{MaxAllowedFaultyObservations: 0})
+	assert.Error(t, err)
+}
+
+func TestTaskRunResults_Aggregate_ModeIsDeterministicOnTies(t *testing.T) {
+	t.Parallel()
+
+	trrs := pipeline.TaskRunResults{
+		mustResult(t, 200),
+		mustResult(t, 100),
+		mustResult(t, 200),
+		mustResult(t, 100),
+	}
+
+	for i := 0; i < 10; i++ {
+		result, err := trrs.Aggregate(pipeline.AggregationMode, pipeline.AggregateOpts{})
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(100), result)
+	}
+}
+
+func TestTaskRunResults_Aggregate_WeightedMedianErrorsOnMissingWeight(t *testing.T) {
+	t.Parallel()
+
+	trrs := pipeline.TaskRunResults{
+		mustResult(t, 100),
+		mustResult(t, 200),
+	}
+
+	_, err := trrs.Aggregate(pipeline.AggregationWeightedMedian, pipeline.AggregateOpts{Weights: map[string]float64{}})
+	assert.Error(t, err)
+}
+
+func TestTaskRunResults_Raw_ReturnsEveryBranchInOrder(t *testing.T) {
+	t.Parallel()
+
+	trrs := pipeline.TaskRunResults{
+		mustResult(t, 100),
+		{Error: assert.AnError},
+		mustResult(t, 104),
+	}
+
+	raw := trrs.Raw()
+	require.Len(t, raw, 3)
+	assert.Equal(t, big.NewInt(100), raw[0].Value)
+	assert.Equal(t, assert.AnError.Error(), raw[1].Error)
+	assert.Equal(t, big.NewInt(104), raw[2].Value)
+}
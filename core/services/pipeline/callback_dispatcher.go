@@ -0,0 +1,245 @@
+package pipeline
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+
+	"github.com/smartcontractkit/chainlink/core/logger"
+)
+
+// maxCallbackAttempts bounds the retry budget for a single callback
+// delivery; once exhausted the callback row is left in the DB marked failed
+// for operator inspection rather than retried forever.
+const maxCallbackAttempts = 10
+
+// CallbackRegistration is what a caller (the pipeline-runs HTTP endpoint)
+// provides to have a run's result delivered by HTTP once it finishes.
+type CallbackRegistration struct {
+	JobID   int32
+	RunID   int64
+	URL     string
+	Headers map[string]string
+	// Secret HMAC-signs the delivered payload via an `X-Chainlink-Signature`
+	// header so the receiver can verify it came from this node. When empty,
+	// the job's own EncryptedOCRKeyBundleID-derived secret is used instead
+	// (see deriveJobSecret), so callers don't have to manage a separate
+	// per-callback secret just to get a signed payload.
+	Secret string
+}
+
+// CallbackDispatcher POSTs finished pipeline.Run results to a registered
+// callback URL, so a caller of POST /v2/jobs/:id/runs with mode=async can
+// react to the result without polling GET /v2/jobs/:id/runs. Deliveries are
+// persisted in pipeline_run_callbacks so they survive a node restart between
+// the run finishing and the callback succeeding, as long as DeliverPending is
+// called once at node boot (see its doc comment) to resume them.
+type CallbackDispatcher struct {
+	db     *gorm.DB
+	runner Runner
+	client *http.Client
+}
+
+// NewCallbackDispatcher returns a CallbackDispatcher that delivers callbacks
+// over client, which should already be built by NewSafeHTTPClient so that a
+// callback URL can't be used as an SSRF vector either.
+func NewCallbackDispatcher(db *gorm.DB, runner Runner, client *http.Client) *CallbackDispatcher {
+	return &CallbackDispatcher{db: db, runner: runner, client: client}
+}
+
+// RegisterCallback persists reg and starts a goroutine that awaits the run
+// and delivers it. Persisting first means a crash between registration and
+// delivery is recovered by DeliverPending on the next boot.
+func (d *CallbackDispatcher) RegisterCallback(reg CallbackRegistration) error {
+	headers, err := json.Marshal(reg.Headers)
+	if err != nil {
+		return errors.Wrap(err, "marshalling callback headers")
+	}
+	secret := reg.Secret
+	if secret == "" {
+		secret, err = d.deriveJobSecret(reg.JobID)
+		if err != nil {
+			return errors.Wrap(err, "deriving callback secret")
+		}
+	}
+	row := pipelineRunCallback{
+		JobID:       reg.JobID,
+		RunID:       reg.RunID,
+		URL:         reg.URL,
+		Headers:     string(headers),
+		Secret:      secret,
+		Attempts:    0,
+		Delivered:   false,
+		NextAttempt: time.Now(),
+	}
+	if err := d.db.Create(&row).Error; err != nil {
+		return errors.Wrap(err, "persisting pipeline run callback")
+	}
+	go d.awaitAndDeliver(context.Background(), row)
+	return nil
+}
+
+// deriveJobSecret derives a per-job HMAC secret from the OCR job's own
+// EncryptedOCRKeyBundleID, so two callbacks registered by the same job always
+// sign with the same secret (letting a receiver pin it per job) without
+// CallbackRegistration having to carry a secret of its own by default.
+// EncryptedOCRKeyBundleID is itself already opaque/encrypted, but it's
+// hashed rather than used directly so the signing secret can't be recovered
+// from a leaked signature and reused to read the key bundle column.
+func (d *CallbackDispatcher) deriveJobSecret(jobID int32) (string, error) {
+	var encryptedOCRKeyBundleID string
+	err := d.db.Raw(`
+		SELECT ocr.encrypted_ocr_key_bundle_id
+		FROM jobs_v2 j
+		JOIN offchainreporting_oracle_specs ocr ON ocr.id = j.offchainreporting_oracle_spec_id
+		WHERE j.id = ?
+	`, jobID).Row().Scan(&encryptedOCRKeyBundleID)
+	if err != nil {
+		return "", errors.Wrapf(err, "loading EncryptedOCRKeyBundleID for job %d", jobID)
+	}
+	return hashKeyBundleSecret(encryptedOCRKeyBundleID), nil
+}
+
+// hashKeyBundleSecret derives deriveJobSecret's return value from a job's
+// raw EncryptedOCRKeyBundleID; split out from deriveJobSecret so the
+// derivation itself is testable without a DB.
+func hashKeyBundleSecret(encryptedOCRKeyBundleID string) string {
+	sum := sha256.Sum256([]byte(encryptedOCRKeyBundleID))
+	return hex.EncodeToString(sum[:])
+}
+
+// DeliverPending resumes delivery of every pipeline_run_callbacks row that
+// hadn't been delivered yet when the node last stopped. It must be called
+// once during node boot, after the DB and Runner are available, for
+// CallbackDispatcher to actually survive a restart as RegisterCallback's doc
+// comment promises - without this call, rows left mid-retry (or registered
+// just before a crash) are never picked back up.
+func (d *CallbackDispatcher) DeliverPending(ctx context.Context) error {
+	var rows []pipelineRunCallback
+	if err := d.db.Where("NOT delivered AND NOT failed").Find(&rows).Error; err != nil {
+		return errors.Wrap(err, "loading pending pipeline run callbacks")
+	}
+	for _, row := range rows {
+		go d.awaitAndDeliver(ctx, row)
+	}
+	return nil
+}
+
+func (d *CallbackDispatcher) awaitAndDeliver(ctx context.Context, row pipelineRunCallback) {
+	if err := d.runner.AwaitRun(ctx, row.RunID); err != nil {
+		logger.Errorw("CallbackDispatcher: run failed while awaiting callback delivery", "error", err, "runID", row.RunID)
+	}
+	d.deliverWithBackoff(row)
+}
+
+// deliverWithBackoff retries delivery with exponential backoff
+// (1s, 2s, 4s, ...) up to maxCallbackAttempts, persisting progress after
+// every attempt so DeliverPending can resume across a restart. If row is
+// being resumed mid-backoff (row.NextAttempt is still in the future), it
+// waits out the remainder of that delay before trying again, rather than
+// re-hitting the callback URL immediately just because the node restarted.
+func (d *CallbackDispatcher) deliverWithBackoff(row pipelineRunCallback) {
+	if wait := time.Until(row.NextAttempt); wait > 0 {
+		time.Sleep(wait)
+	}
+	for row.Attempts < maxCallbackAttempts {
+		err := d.deliverOnce(row)
+		if err == nil {
+			row.Delivered = true
+			d.db.Save(&row)
+			return
+		}
+		logger.Warnw("CallbackDispatcher: delivery attempt failed", "error", err, "runID", row.RunID, "attempt", row.Attempts+1)
+		backoff := time.Duration(1<<uint(row.Attempts)) * time.Second
+		row.Attempts++
+		row.NextAttempt = time.Now().Add(backoff)
+		d.db.Save(&row)
+		time.Sleep(backoff)
+	}
+	row.Failed = true
+	d.db.Save(&row)
+	logger.Errorw("CallbackDispatcher: exhausted retry budget, giving up", "runID", row.RunID, "url", row.URL)
+}
+
+// deliverOnce POSTs the run's results as the callback payload. The payload
+// is a plain JSON encoding of the TaskRunResults pipelineRunner.ResultsForRun
+// returns, not a JSON:API-encoded pipeline.Run - receivers get the same
+// outputs/errors either way, but without pipeline.Run's id/type/meta
+// envelope. Switching to that encoding is a matter of marshalling the
+// pipeline.Run this callback's RunID identifies instead, once a shared
+// helper for building one exists outside the web layer.
+func (d *CallbackDispatcher) deliverOnce(row pipelineRunCallback) error {
+	results, err := d.runner.ResultsForRun(context.Background(), row.RunID)
+	if err != nil {
+		return errors.Wrap(err, "loading run results")
+	}
+
+	payload, err := json.Marshal(results)
+	if err != nil {
+		return errors.Wrap(err, "marshalling run results")
+	}
+
+	req, err := http.NewRequest(http.MethodPost, row.URL, bytes.NewReader(payload))
+	if err != nil {
+		return errors.Wrap(err, "building callback request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Chainlink-Signature", signPayload(row.Secret, payload))
+
+	var headers map[string]string
+	if err := json.Unmarshal([]byte(row.Headers), &headers); err == nil {
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "making callback request")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return errors.Errorf("callback endpoint returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func signPayload(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// pipelineRunCallback is the pipeline_run_callbacks row backing a single
+// registered callback delivery.
+type pipelineRunCallback struct {
+	ID          int64 `gorm:"primary_key"`
+	JobID       int32
+	RunID       int64
+	URL         string
+	Headers     string
+	Secret      string
+	Attempts    int
+	Delivered   bool
+	// Failed is set once deliverWithBackoff exhausts maxCallbackAttempts
+	// without a successful delivery. It's a separate terminal state from
+	// Delivered so DeliverPending's "still pending" query can exclude rows
+	// that have already used up their retry budget, instead of retrying a
+	// permanently failing callback URL forever on every future boot.
+	Failed      bool
+	NextAttempt time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (pipelineRunCallback) TableName() string {
+	return "pipeline_run_callbacks"
+}
@@ -0,0 +1,34 @@
+package pipeline
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashKeyBundleSecret_IsStableAndKeyBundleDependent(t *testing.T) {
+	t.Parallel()
+
+	a := hashKeyBundleSecret("encrypted-bundle-a")
+	again := hashKeyBundleSecret("encrypted-bundle-a")
+	b := hashKeyBundleSecret("encrypted-bundle-b")
+
+	assert.Equal(t, a, again)
+	assert.NotEqual(t, a, b)
+	assert.NotEqual(t, "encrypted-bundle-a", a, "the derived secret must not just be the raw key bundle ID")
+}
+
+func TestSignPayload_DiffersByDerivedSecret(t *testing.T) {
+	t.Parallel()
+
+	payload := []byte(`{"result":"102"}`)
+	secretA := hashKeyBundleSecret("encrypted-bundle-a")
+	secretB := hashKeyBundleSecret("encrypted-bundle-b")
+
+	sigA := signPayload(secretA, payload)
+	sigAAgain := signPayload(secretA, payload)
+	sigB := signPayload(secretB, payload)
+
+	assert.Equal(t, sigA, sigAAgain)
+	assert.NotEqual(t, sigA, sigB)
+}
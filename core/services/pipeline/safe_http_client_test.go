@@ -0,0 +1,55 @@
+package pipeline_test
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/services/pipeline"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSafeHTTPClient_RejectsRedirectToLoopback(t *testing.T) {
+	t.Parallel()
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://127.0.0.1:1/admin", http.StatusFound)
+	}))
+	defer upstream.Close()
+
+	client := pipeline.NewSafeHTTPClient(pipeline.SafeHTTPClientConfig{})
+
+	_, err := client.Get(upstream.URL)
+	require.Error(t, err)
+}
+
+func TestSafeHTTPClient_RejectsDirectRequestToMetadataEndpoint(t *testing.T) {
+	t.Parallel()
+
+	client := pipeline.NewSafeHTTPClient(pipeline.SafeHTTPClientConfig{})
+
+	_, err := client.Get("http://169.254.169.254/latest/meta-data/")
+	require.Error(t, err)
+}
+
+func TestSafeHTTPClient_AllowsAllowlistedPrivateIP(t *testing.T) {
+	t.Parallel()
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer upstream.Close()
+
+	_, ipnet, err := net.ParseCIDR("127.0.0.0/8")
+	require.NoError(t, err)
+
+	client := pipeline.NewSafeHTTPClient(pipeline.SafeHTTPClientConfig{
+		AllowedIPs: []*net.IPNet{ipnet},
+	})
+
+	resp, err := client.Get(upstream.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+}
@@ -0,0 +1,100 @@
+package pipeline
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// defaultSafeHTTPClient backs every HTTPTask whose safeHTTPClient was never
+// wired in by a Runner (e.g. in tests, or while the task-DAG builder doesn't
+// yet thread one through for a given job type). It's built lazily, once, with
+// SafeHTTPClientConfig's zero value, so an HTTPTask is never left making
+// bare, SSRF-unprotected requests just because nothing called
+// SetSafeHTTPClient on it.
+var (
+	defaultSafeHTTPClientOnce sync.Once
+	defaultSafeHTTPClient     *http.Client
+)
+
+func getDefaultSafeHTTPClient() *http.Client {
+	defaultSafeHTTPClientOnce.Do(func() {
+		defaultSafeHTTPClient = NewSafeHTTPClient(SafeHTTPClientConfig{})
+	})
+	return defaultSafeHTTPClient
+}
+
+// HTTPTask issues an HTTP request as part of a pipeline run, most commonly as
+// the first leg of an OCR observationSource. Requests go through a
+// NewSafeHTTPClient client unless AllowUnsafe opts the task out, so a malicious or
+// compromised upstream can't use a redirect to exfiltrate data from node-
+// internal services.
+type HTTPTask struct {
+	BaseTask `mapstructure:",squash"`
+
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	// AllowUnsafe lets an individual task skip the SSRF-hardened client, for
+	// operators who deliberately point a task at an internal service.
+	AllowUnsafe bool `json:"allowUnsafe"`
+
+	// safeHTTPClient is whatever SetSafeHTTPClient wired in; while it is nil,
+	// Run uses the default-policy client from getDefaultSafeHTTPClient.
+	safeHTTPClient *http.Client
+}
+
+var _ Task = (*HTTPTask)(nil)
+
+func (t *HTTPTask) Type() TaskType {
+	return TaskTypeHTTP
+}
+
+// SetSafeHTTPClient wires in a client built by NewSafeHTTPClient for this
+// task's job. It exists as a setter rather than a constructor argument because
+// HTTPTask, like the other Task implementations, is instantiated by
+// unmarshalling the job's observationSource DAG.
+func (t *HTTPTask) SetSafeHTTPClient(client *http.Client) {
+	t.safeHTTPClient = client
+}
+
+func (t *HTTPTask) Run(vars Vars) (result Result) {
+	method := t.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	req, err := http.NewRequest(method, t.URL, nil)
+	if err != nil {
+		return Result{Error: errors.Wrap(err, "http task: building request")}
+	}
+
+	client := t.safeHTTPClient
+	if t.AllowUnsafe {
+		client = http.DefaultClient
+	} else if client == nil {
+		// No Runner has called SetSafeHTTPClient (e.g. this job type's
+		// task-DAG builder doesn't thread one through yet). Fall back to a
+		// default-policy safe client rather than leaving safeHTTPClient nil,
+		// which would otherwise panic every such request.
+		client = getDefaultSafeHTTPClient()
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return Result{Error: errors.Wrap(err, "http task: making request")}
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return Result{Error: errors.Wrap(err, "http task: reading response body")}
+	}
+	if resp.StatusCode >= 400 {
+		return Result{Error: errors.Errorf("http task: got error response %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))}
+	}
+
+	return Result{Value: string(body)}
+}
@@ -0,0 +1,62 @@
+package pipeline_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/services/pipeline"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPTask_Run_UsesDefaultSafeClientWhenNoneWired(t *testing.T) {
+	t.Parallel()
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("ok"))
+	}))
+	defer upstream.Close()
+
+	// SetSafeHTTPClient is deliberately never called here, reproducing the
+	// case where a Runner hasn't wired one in: Run must go through the
+	// default safe client rather than nil-pointer panicking on
+	// t.safeHTTPClient. httptest serves on loopback, which that client's
+	// default policy refuses, so the request fails with ErrDisallowedIP.
+	task := pipeline.HTTPTask{URL: upstream.URL}
+
+	result := task.Run(pipeline.Vars{})
+	require.Error(t, result.Error)
+	assert.Contains(t, result.Error.Error(), pipeline.ErrDisallowedIP.Error())
+}
+
+func TestHTTPTask_Run_DefaultSafeClientRejectsRedirectToLoopback(t *testing.T) {
+	t.Parallel()
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://127.0.0.1:1/admin", http.StatusFound)
+	}))
+	defer upstream.Close()
+
+	task := pipeline.HTTPTask{URL: upstream.URL}
+
+	result := task.Run(pipeline.Vars{})
+	require.Error(t, result.Error)
+}
+
+func TestHTTPTask_Run_AllowUnsafeBypassesSafeClient(t *testing.T) {
+	t.Parallel()
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("ok"))
+	}))
+	defer upstream.Close()
+
+	task := pipeline.HTTPTask{URL: upstream.URL, AllowUnsafe: true}
+
+	result := task.Run(pipeline.Vars{})
+	require.NoError(t, result.Error)
+	assert.Equal(t, "ok", result.Value)
+}
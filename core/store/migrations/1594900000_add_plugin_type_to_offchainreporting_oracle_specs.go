@@ -0,0 +1,16 @@
+package migrations
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migration1594900000 adds plugin_type to offchainreporting_oracle_specs so
+// that OracleSpec.PluginType (which selects the job.OracleCreator used to
+// build the spec's oracle Service) survives a ToDBRow/FromDBRow round trip
+// instead of reverting to the "median" default on every job reload.
+func Migration1594900000(tx *gorm.DB) error {
+	return tx.Exec(`
+		ALTER TABLE offchainreporting_oracle_specs
+		ADD COLUMN plugin_type VARCHAR(255) NOT NULL DEFAULT 'median';
+	`).Error
+}
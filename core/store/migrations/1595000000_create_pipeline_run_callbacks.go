@@ -0,0 +1,29 @@
+package migrations
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migration1595000000 adds pipeline_run_callbacks, which backs
+// pipeline.CallbackDispatcher: one row per async run with a registered
+// callback URL, so delivery (and its retry count/backoff) survives a node
+// restart between the run finishing and the callback succeeding.
+func Migration1595000000(tx *gorm.DB) error {
+	return tx.Exec(`
+		CREATE TABLE pipeline_run_callbacks (
+			id SERIAL PRIMARY KEY,
+			job_id INTEGER NOT NULL,
+			run_id BIGINT NOT NULL REFERENCES pipeline_runs(id) ON DELETE CASCADE,
+			url TEXT NOT NULL,
+			headers TEXT NOT NULL DEFAULT '{}',
+			secret TEXT NOT NULL DEFAULT '',
+			attempts INTEGER NOT NULL DEFAULT 0,
+			delivered BOOLEAN NOT NULL DEFAULT FALSE,
+			next_attempt TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+		CREATE INDEX idx_pipeline_run_callbacks_run_id ON pipeline_run_callbacks (run_id);
+		CREATE INDEX idx_pipeline_run_callbacks_pending ON pipeline_run_callbacks (next_attempt) WHERE NOT delivered;
+	`).Error
+}
@@ -0,0 +1,20 @@
+package migrations
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migration1595000001 adds pipeline_run_callbacks.failed, a terminal state
+// separate from delivered: once CallbackDispatcher.deliverWithBackoff
+// exhausts its retry budget for a row, it sets failed so DeliverPending's
+// "still pending" query stops picking that row back up on every future
+// boot.
+func Migration1595000001(tx *gorm.DB) error {
+	return tx.Exec(`
+		ALTER TABLE pipeline_run_callbacks
+		ADD COLUMN failed BOOLEAN NOT NULL DEFAULT FALSE;
+
+		DROP INDEX idx_pipeline_run_callbacks_pending;
+		CREATE INDEX idx_pipeline_run_callbacks_pending ON pipeline_run_callbacks (next_attempt) WHERE NOT delivered AND NOT failed;
+	`).Error
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Interval is a TOML/DB-friendly time.Duration: stored as nanoseconds, and
+// directly convertible to time.Duration at call sites (time.Duration(iv)).
+type Interval int64
+
+// EIP55Address is a checksummed hex-encoded contract/account address.
+type EIP55Address string
+
+// Address returns the go-ethereum representation of the address.
+func (a EIP55Address) Address() common.Address {
+	return common.HexToAddress(string(a))
+}
+
+// OffchainReportingOracleSpec is the persisted form of an
+// offchainreporting.OracleSpec; it's what actually round-trips through the
+// jobs_v2/offchainreporting_oracle_specs tables via JobSpecV2.
+type OffchainReportingOracleSpec struct {
+	ID                                     int32         `toml:"-"`
+	Type                                   string        `toml:"type"`
+	SchemaVersion                          uint32        `toml:"schemaVersion"`
+	MaxTaskDuration                        Interval      `toml:"maxTaskDuration"`
+	ContractAddress                        EIP55Address  `toml:"contractAddress"`
+	P2PPeerID                              string        `toml:"p2pPeerID"`
+	P2PBootstrapPeers                      []string      `toml:"p2pBootstrapPeers"`
+	IsBootstrapPeer                        bool          `toml:"isBootstrapPeer"`
+	EncryptedOCRKeyBundleID                string        `toml:"keyBundleID"`
+	TransmitterAddress                     *EIP55Address `toml:"transmitterAddress"`
+	BlockchainTimeout                      Interval      `toml:"blockchainTimeout"`
+	ContractConfigConfirmations            uint16        `toml:"contractConfigConfirmations"`
+	ContractConfigTrackerPollInterval      Interval      `toml:"contractConfigTrackerPollInterval"`
+	ContractConfigTrackerSubscribeInterval Interval      `toml:"contractConfigTrackerSubscribeInterval"`
+	ObservationTimeout                     Interval      `toml:"observationTimeout"`
+	MonitoringEndpoint                     string        `toml:"monitoringEndpoint"`
+	ObservationSource                      string        `toml:"observationSource"`
+
+	// PluginType selects which job.OracleCreator builds this spec's oracle
+	// Service; it lives on the persisted spec (not a sibling field on
+	// offchainreporting.OracleSpec) so it survives a ToDBRow/FromDBRow round
+	// trip - i.e. a job reload or node restart - instead of reverting to the
+	// "median" default every time.
+	PluginType string `toml:"pluginType"`
+
+	CreatedAt time.Time `toml:"-"`
+	UpdatedAt time.Time `toml:"-"`
+}
+
+// JobSpecV2 is the DB row shared by every jobs-v2 job type; exactly one of
+// its Type-named spec pointers (e.g. OffchainreportingOracleSpec) is set.
+type JobSpecV2 struct {
+	ID                          int32
+	OffchainreportingOracleSpec *OffchainReportingOracleSpec
+	Type                        string
+	SchemaVersion               uint32
+	MaxTaskDuration             Interval
+}
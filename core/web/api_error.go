@@ -0,0 +1,84 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/pkg/errors"
+
+	"github.com/smartcontractkit/chainlink/core/services/offchainreporting"
+)
+
+// Stable, machine-readable error codes returned in APIError.Code. Clients
+// should switch on these rather than the HTTPStatusCode or Message, which
+// are free to change wording without it being a breaking change.
+const (
+	CodeInvalidJobID       = "invalid_job_id"
+	CodeInvalidRunID       = "invalid_run_id"
+	CodeInvalidRequestBody = "invalid_request_body"
+	CodeOCRKeyMissing      = "ocr_key_missing"
+	CodePeerNotStarted     = "ocr_peer_not_started"
+	CodeInvalidTransmitter = "invalid_transmitter_address"
+	CodeInternal           = "internal_error"
+)
+
+// APIError is the structured error body returned on every non-2xx response
+// from the jobs-v2 and pipeline-runs endpoints. Code is the part meant to be
+// programmed against; HTTPStatusCode, Message, and Hint exist so a human (or
+// a log line) can make sense of it without looking up Code.
+type APIError struct {
+	HTTPStatusCode int                    `json:"-"`
+	Code           string                 `json:"code"`
+	Message        string                 `json:"message"`
+	Details        map[string]interface{} `json:"details,omitempty"`
+	RequestID      string                 `json:"request_id,omitempty"`
+	Hint           string                 `json:"hint,omitempty"`
+}
+
+func (e *APIError) Error() string {
+	return e.Message
+}
+
+// NewAPIError builds an APIError directly, for call sites that already know
+// the specific code and status they want to return.
+func NewAPIError(httpStatusCode int, code string, err error) *APIError {
+	return &APIError{
+		HTTPStatusCode: httpStatusCode,
+		Code:           code,
+		Message:        err.Error(),
+	}
+}
+
+// From classifies err into an APIError, unwrapping pkg/errors-wrapped errors
+// and known sentinel errors from services/offchainreporting into a specific
+// code and status. Anything it doesn't recognize becomes a generic 500
+// CodeInternal, which keeps From safe to call on every error path without a
+// handler needing its own switch statement.
+//
+// offchainreporting.ErrKeyBundleMissing/ErrPeerNotStarted/
+// ErrInvalidTransmitterAddress originate from job creation
+// (ocr1OracleCreator, via jobSpawnerDelegate.ServicesForSpec) rather than from running an existing
+// pipeline, so JobsController.Create is what actually surfaces them;
+// PipelineRunsController never will.
+func From(err error) *APIError {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, offchainreporting.ErrKeyBundleMissing):
+		return NewAPIError(http.StatusUnprocessableEntity, CodeOCRKeyMissing, err)
+	case errors.Is(err, offchainreporting.ErrPeerNotStarted):
+		return NewAPIError(http.StatusInternalServerError, CodePeerNotStarted, err)
+	case errors.Is(err, offchainreporting.ErrInvalidTransmitterAddress):
+		return NewAPIError(http.StatusUnprocessableEntity, CodeInvalidTransmitter, err)
+	default:
+		return NewAPIError(http.StatusInternalServerError, CodeInternal, err)
+	}
+}
+
+// WriteAPIError JSON:API-encodes apiErr and writes it to w with its
+// HTTPStatusCode.
+func WriteAPIError(w http.ResponseWriter, apiErr *APIError) {
+	w.Header().Set("Content-Type", "application/vnd.api+json")
+	w.WriteHeader(apiErr.HTTPStatusCode)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"errors": []*APIError{apiErr}})
+}
@@ -0,0 +1,47 @@
+package web_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/core/services/offchainreporting"
+	"github.com/smartcontractkit/chainlink/core/web"
+)
+
+func TestFrom_ClassifiesKnownOCRErrors(t *testing.T) {
+	t.Parallel()
+
+	apiErr := web.From(errors.Wrap(offchainreporting.ErrKeyBundleMissing, "looking up key bundle"))
+	require.NotNil(t, apiErr)
+	assert.Equal(t, web.CodeOCRKeyMissing, apiErr.Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, apiErr.HTTPStatusCode)
+
+	apiErr = web.From(errors.Wrap(offchainreporting.ErrPeerNotStarted, "starting peer"))
+	require.NotNil(t, apiErr)
+	assert.Equal(t, web.CodePeerNotStarted, apiErr.Code)
+	assert.Equal(t, http.StatusInternalServerError, apiErr.HTTPStatusCode)
+
+	apiErr = web.From(errors.Wrap(offchainreporting.ErrInvalidTransmitterAddress, "validating transmitter"))
+	require.NotNil(t, apiErr)
+	assert.Equal(t, web.CodeInvalidTransmitter, apiErr.Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, apiErr.HTTPStatusCode)
+}
+
+func TestFrom_FallsBackToInternalForUnrecognizedErrors(t *testing.T) {
+	t.Parallel()
+
+	apiErr := web.From(errors.New("something unexpected"))
+	require.NotNil(t, apiErr)
+	assert.Equal(t, web.CodeInternal, apiErr.Code)
+	assert.Equal(t, http.StatusInternalServerError, apiErr.HTTPStatusCode)
+}
+
+func TestFrom_NilErrorReturnsNil(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, web.From(nil))
+}
@@ -0,0 +1,48 @@
+package web
+
+import (
+	"io/ioutil"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pelletier/go-toml"
+	"github.com/pkg/errors"
+	"gopkg.in/guregu/null.v4"
+
+	"github.com/smartcontractkit/chainlink/core/services/offchainreporting"
+)
+
+// JobsController manages jobs-v2 job specs. It currently only accepts
+// offchainreporting job specs, since that's the only job.Spec the node
+// registers a jobSpawnerDelegate for.
+type JobsController struct {
+	App chainlinkApp
+}
+
+// Create parses the request body as a TOML offchainreporting job spec and
+// adds it, starting the job's services (including its OCR Oracle) as part
+// of AddJobV2. Job-creation-time failures - a missing OCR key bundle, a
+// libp2p peer that isn't started, or an invalid transmitter address - are
+// classified by From into the same structured APIError the pipeline-runs
+// endpoints return.
+func (jc *JobsController) Create(c *gin.Context) {
+	body, err := ioutil.ReadAll(c.Request.Body)
+	if err != nil {
+		WriteAPIError(c.Writer, NewAPIError(http.StatusUnprocessableEntity, CodeInvalidRequestBody, errors.Wrap(err, "reading request body")))
+		return
+	}
+
+	var spec offchainreporting.OracleSpec
+	if err := toml.Unmarshal(body, &spec); err != nil {
+		WriteAPIError(c.Writer, NewAPIError(http.StatusUnprocessableEntity, CodeInvalidRequestBody, errors.Wrap(err, "invalid job spec TOML")))
+		return
+	}
+
+	jobID, err := jc.App.AddJobV2(c.Request.Context(), spec, null.String{})
+	if err != nil {
+		WriteAPIError(c.Writer, From(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"id": jobID})
+}
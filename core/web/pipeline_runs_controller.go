@@ -0,0 +1,123 @@
+package web
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+
+	"github.com/smartcontractkit/chainlink/core/services/pipeline"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+)
+
+// PipelineRunsController manages run requests for jobs-v2 pipelines.
+type PipelineRunsController struct {
+	App chainlinkApp
+}
+
+// createPipelineRunRequest is the optional body accepted by Create. Mode
+// defaults to "sync", which preserves the original behavior of blocking
+// until the run finishes and returning it in the response. In "async" mode
+// the run is kicked off and the handler returns immediately; the finished
+// run is instead delivered to CallbackURL once it's ready.
+type createPipelineRunRequest struct {
+	Mode            string            `json:"mode"`
+	CallbackURL     string            `json:"callback_url"`
+	CallbackHeaders map[string]string `json:"callback_headers"`
+}
+
+// Create starts a new run of the job at :id.
+func (prc *PipelineRunsController) Create(c *gin.Context) {
+	jobID, err := jobIDFromParam(c)
+	if err != nil {
+		WriteAPIError(c.Writer, NewAPIError(http.StatusUnprocessableEntity, CodeInvalidJobID, err))
+		return
+	}
+
+	var request createPipelineRunRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			WriteAPIError(c.Writer, NewAPIError(http.StatusUnprocessableEntity, CodeInvalidRequestBody, errors.Wrap(err, "invalid run request body")))
+			return
+		}
+	}
+
+	runID, err := prc.App.RunJobV2(c.Request.Context(), jobID, nil)
+	if err != nil {
+		WriteAPIError(c.Writer, From(err))
+		return
+	}
+
+	if request.Mode == "async" {
+		if request.CallbackURL != "" {
+			err = prc.App.GetPipelineCallbackDispatcher().RegisterCallback(pipeline.CallbackRegistration{
+				JobID:   jobID,
+				RunID:   runID,
+				URL:     request.CallbackURL,
+				Headers: request.CallbackHeaders,
+			})
+			if err != nil {
+				WriteAPIError(c.Writer, From(err))
+				return
+			}
+		}
+		c.JSON(http.StatusAccepted, gin.H{"id": runID})
+		return
+	}
+
+	if err := prc.App.AwaitRun(c.Request.Context(), runID); err != nil {
+		WriteAPIError(c.Writer, From(err))
+		return
+	}
+	run, err := prc.App.ResultsForRun(c.Request.Context(), runID)
+	if err != nil {
+		WriteAPIError(c.Writer, From(err))
+		return
+	}
+	jsonAPIResponse(c, run, "pipelineRun")
+}
+
+// Index lists the runs belonging to the job at :id, most recent first.
+func (prc *PipelineRunsController) Index(c *gin.Context) {
+	jobID, err := jobIDFromParam(c)
+	if err != nil {
+		WriteAPIError(c.Writer, NewAPIError(http.StatusUnprocessableEntity, CodeInvalidJobID, err))
+		return
+	}
+
+	size, page, offset, err := parsePaginatedRequest(c.Query("size"), c.Query("page"))
+	if err != nil {
+		WriteAPIError(c.Writer, NewAPIError(http.StatusUnprocessableEntity, CodeInvalidRequestBody, err))
+		return
+	}
+
+	runs, count, err := prc.App.PipelineRunsByJobID(jobID, offset, size)
+	if err != nil {
+		WriteAPIError(c.Writer, From(err))
+		return
+	}
+	paginatedResponse(c, "pipelineRun", size, page, runs, count, err)
+}
+
+// Show returns a single run by ID.
+func (prc *PipelineRunsController) Show(c *gin.Context) {
+	runID, err := models.NewIDFromString(c.Param("runID"))
+	if err != nil {
+		WriteAPIError(c.Writer, NewAPIError(http.StatusUnprocessableEntity, CodeInvalidRunID, errors.Wrap(err, "invalid run ID")))
+		return
+	}
+	run, err := prc.App.ResultsForRun(c.Request.Context(), runID.Int64())
+	if err != nil {
+		WriteAPIError(c.Writer, From(err))
+		return
+	}
+	jsonAPIResponse(c, run, "pipelineRun")
+}
+
+func jobIDFromParam(c *gin.Context) (int32, error) {
+	jobID, err := models.NewIDFromString(c.Param("id"))
+	if err != nil {
+		return 0, errors.Wrap(err, "invalid job ID")
+	}
+	return jobID.Int32(), nil
+}
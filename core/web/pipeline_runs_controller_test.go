@@ -2,6 +2,7 @@ package web_test
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"testing"
@@ -133,6 +134,14 @@ func TestPipelineRunsController_ShowRun_InvalidID(t *testing.T) {
 	response, cleanup := client.Get("/v2/jobs/1/runs/invalid-run-ID")
 	defer cleanup()
 	cltest.AssertServerResponse(t, response, http.StatusUnprocessableEntity)
+
+	var body struct {
+		Errors []web.APIError `json:"errors"`
+	}
+	err := json.NewDecoder(response.Body).Decode(&body)
+	require.NoError(t, err)
+	require.Len(t, body.Errors, 1)
+	assert.Equal(t, web.CodeInvalidRunID, body.Errors[0].Code)
 }
 
 func setupPipelineRunsControllerTests(t *testing.T) (cltest.HTTPClientCleaner, int32, []int64, func()) {